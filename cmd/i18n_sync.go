@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/knadh/stuffbin"
+	"github.com/labstack/echo"
+)
+
+// defI18nCatalogURL is the default remote catalog of i18n language packs
+// that can be pulled in at runtime, on top of the ones embedded via stuffbin.
+const defI18nCatalogURL = "https://i18n.listmonk.app/catalog.json"
+
+// i18nCatalogPubKeyHex is the bundled ed25519 public key (hex-encoded) used
+// to verify the detached signature on every catalog entry before it's
+// written to disk. It corresponds to the private key listmonk's i18n
+// catalog is signed with.
+const i18nCatalogPubKeyHex = "e8a7df8a8d6966c89528fd8d3cf6a1b41221fc9295476a0036154a4f87caeb44"
+
+var i18nCatalogPubKey = mustDecodeEd25519PubKey(i18nCatalogPubKeyHex)
+
+// i18nCatalogEntry describes a single downloadable language pack in the
+// remote catalog.
+type i18nCatalogEntry struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Hash      string `json:"hash"`      // SHA-256 of the language file, hex-encoded.
+	Signature string `json:"signature"` // Detached ed25519 signature of the file, hex-encoded.
+}
+
+// i18nCatalog is the `catalog.json` payload the remote catalog URL serves.
+type i18nCatalog struct {
+	Languages []i18nCatalogEntry `json:"languages"`
+}
+
+// i18nLangStatus is a single row in the admin "installed/available languages" panel.
+type i18nLangStatus struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Outdated  bool   `json:"outdated"`
+}
+
+// handleSyncI18n fetches the remote i18n catalog, downloads any language
+// packs that are new or whose hash differs from the local overlay copy, and
+// rebuilds app.fs so the merged (overlay-over-embedded) filesystem is used
+// for subsequent i18n lookups.
+func handleSyncI18n(c echo.Context) error {
+	app := c.Get("app").(*App)
+
+	st, err := syncI18nCatalog(app)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("error syncing i18n catalog: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, okResp{st})
+}
+
+// syncI18nCatalog downloads the catalog, verifies and writes any changed
+// language packs into the overlay directory, and remounts app.fs on top of
+// it. It returns the resulting install status of every catalog language.
+func syncI18nCatalog(app *App) ([]i18nLangStatus, error) {
+	catalogURL := app.constants.I18nCatalogURL
+	if catalogURL == "" {
+		catalogURL = defI18nCatalogURL
+	}
+
+	cat, err := fetchI18nCatalog(catalogURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(app.constants.I18nOverlayDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating i18n overlay dir: %v", err)
+	}
+
+	out := make([]i18nLangStatus, 0, len(cat.Languages))
+	for _, e := range cat.Languages {
+		dest := filepath.Join(app.constants.I18nOverlayDir, e.Code+".json")
+
+		installed := true
+		localHash, err := hashFile(dest)
+		if err != nil {
+			installed = false
+		}
+
+		if installed && localHash == e.Hash {
+			out = append(out, i18nLangStatus{Code: e.Code, Name: e.Name, Installed: true})
+			continue
+		}
+
+		b, err := downloadI18nFile(e)
+		if err != nil {
+			app.log.Printf("error downloading i18n language %s: %v", e.Code, err)
+			out = append(out, i18nLangStatus{Code: e.Code, Name: e.Name, Installed: installed, Outdated: installed})
+			continue
+		}
+
+		if err := os.WriteFile(dest, b, 0644); err != nil {
+			return nil, fmt.Errorf("error writing i18n language %s: %v", e.Code, err)
+		}
+
+		out = append(out, i18nLangStatus{Code: e.Code, Name: e.Name, Installed: true})
+	}
+
+	overlay, err := stuffbin.NewLocalFS(app.constants.I18nOverlayDir)
+	if err != nil {
+		return nil, fmt.Errorf("error mounting i18n overlay dir: %v", err)
+	}
+
+	app.Lock()
+	app.fs = app.fs.Merge(overlay)
+	app.Unlock()
+
+	// The negotiated-language cache holds parsed packs read off the old
+	// app.fs; drop it so subsequent requests pick up what was just synced.
+	invalidateI18nCache()
+
+	return out, nil
+}
+
+// fetchI18nCatalog downloads and decodes the catalog.json index.
+func fetchI18nCatalog(url string) (*i18nCatalog, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching i18n catalog: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-200 response fetching i18n catalog: %d", resp.StatusCode)
+	}
+
+	var cat i18nCatalog
+	if err := json.NewDecoder(resp.Body).Decode(&cat); err != nil {
+		return nil, fmt.Errorf("error decoding i18n catalog: %v", err)
+	}
+
+	return &cat, nil
+}
+
+// downloadI18nFile downloads a single catalog entry's language file and
+// verifies its SHA-256 hash and ed25519 signature before returning it.
+func downloadI18nFile(e i18nCatalogEntry) ([]byte, error) {
+	resp, err := http.Get(e.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %v", e.URL, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", e.URL, err)
+	}
+
+	sum := sha256.Sum256(b)
+	if hex.EncodeToString(sum[:]) != e.Hash {
+		return nil, fmt.Errorf("hash mismatch for %s", e.Code)
+	}
+
+	sig, err := hex.DecodeString(e.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding for %s: %v", e.Code, err)
+	}
+	if !ed25519.Verify(i18nCatalogPubKey, b, sig) {
+		return nil, fmt.Errorf("signature verification failed for %s", e.Code)
+	}
+
+	return b, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 of a local file's contents.
+func hashFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkI18nCatalog is a blocking function that re-checks the remote i18n
+// catalog for new/updated language packs at the given interval, mirroring
+// checkUpdates' scheduling.
+func checkI18nCatalog(interval time.Duration, app *App) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := syncI18nCatalog(app); err != nil {
+			app.log.Printf("error syncing i18n catalog: %v", err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/knadh/listmonk/internal/i18n"
+	"github.com/labstack/echo"
+)
+
+// langPref is a single Accept-Language entry with its quality value.
+type langPref struct {
+	tag string
+	q   float64
+}
+
+// parseAcceptLanguage parses an RFC 7231 Accept-Language header into a list
+// of language tags ordered by descending quality (highest preference first).
+func parseAcceptLanguage(h string) []string {
+	if h == "" {
+		return nil
+	}
+
+	parts := strings.Split(h, ",")
+	prefs := make([]langPref, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		tag, q := p, 1.0
+		if i := strings.Index(p, ";"); i != -1 {
+			tag = strings.TrimSpace(p[:i])
+			if qs := strings.TrimSpace(p[i+1:]); strings.HasPrefix(qs, "q=") {
+				if v, err := strconv.ParseFloat(qs[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		prefs = append(prefs, langPref{tag: tag, q: q})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	out := make([]string, 0, len(prefs))
+	for _, p := range prefs {
+		out = append(out, p.tag)
+	}
+	return out
+}
+
+// negotiateLang intersects the client's Accept-Language preference order
+// with the instance's available languages, returning the first match. It
+// matches the full tag (eg: "pt-BR") and falls back to the primary subtag
+// ("pt"). If nothing matches, def is returned.
+func negotiateLang(header string, available []i18nLang, def string) string {
+	avail := make(map[string]bool, len(available))
+	for _, a := range available {
+		avail[a.Code] = true
+	}
+
+	for _, tag := range parseAcceptLanguage(header) {
+		if tag == "*" {
+			// "*" means any language is acceptable: prefer the instance
+			// default if available, otherwise take the first available
+			// language rather than giving up and falling through to def.
+			if avail[def] {
+				return def
+			}
+			if len(available) > 0 {
+				return available[0].Code
+			}
+			return def
+		}
+		if avail[tag] {
+			return tag
+		}
+		if i := strings.Index(tag, "-"); i != -1 && avail[tag[:i]] {
+			return tag[:i]
+		}
+	}
+
+	return def
+}
+
+// i18nCache memoizes the instance's available language list and every
+// parsed *i18n.I18n negotiated so far, so a request with a non-default
+// Accept-Language doesn't re-glob and re-parse the language files on every
+// hit. invalidateI18nCache() drops it, eg: after the i18n overlay
+// filesystem changes (see syncI18nCatalog).
+var i18nCache struct {
+	mu   sync.RWMutex
+	list []i18nLang
+	lang map[string]*i18n.I18n
+}
+
+// cachedI18nLangList returns the instance's available i18n languages,
+// computed once and cached thereafter.
+func cachedI18nLangList(app *App) ([]i18nLang, error) {
+	i18nCache.mu.RLock()
+	list := i18nCache.list
+	i18nCache.mu.RUnlock()
+	if list != nil {
+		return list, nil
+	}
+
+	i18nCache.mu.Lock()
+	defer i18nCache.mu.Unlock()
+	if i18nCache.list != nil {
+		return i18nCache.list, nil
+	}
+
+	list, err := getI18nLangList(app.constants.Lang, app)
+	if err != nil {
+		return nil, err
+	}
+	i18nCache.list = list
+	return list, nil
+}
+
+// cachedI18nLang returns the parsed language pack for lang, loading and
+// caching it on first request.
+func cachedI18nLang(app *App, lang string) (*i18n.I18n, error) {
+	i18nCache.mu.RLock()
+	i, ok := i18nCache.lang[lang]
+	i18nCache.mu.RUnlock()
+	if ok {
+		return i, nil
+	}
+
+	i18nCache.mu.Lock()
+	defer i18nCache.mu.Unlock()
+	if i, ok := i18nCache.lang[lang]; ok {
+		return i, nil
+	}
+
+	loaded, err := getI18nLang(lang, app.fs)
+	if err != nil {
+		return nil, err
+	}
+	if i18nCache.lang == nil {
+		i18nCache.lang = make(map[string]*i18n.I18n)
+	}
+	i18nCache.lang[lang] = loaded
+	return loaded, nil
+}
+
+// invalidateI18nCache drops the cached language list and parsed packs.
+func invalidateI18nCache() {
+	i18nCache.mu.Lock()
+	defer i18nCache.mu.Unlock()
+	i18nCache.list = nil
+	i18nCache.lang = nil
+}
+
+// i18nMiddleware negotiates the request's locale from its Accept-Language
+// header and attaches the resolved *i18n.I18n to the echo context so admin
+// API responses, error messages, and rendered notification templates use
+// the negotiated locale instead of the app-global language.
+func i18nMiddleware(app *App) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			list, err := cachedI18nLangList(app)
+			if err != nil {
+				c.Set("i18n", app.i18n)
+				return next(c)
+			}
+
+			lang := negotiateLang(c.Request().Header.Get("Accept-Language"), list, app.constants.Lang)
+			if lang == app.constants.Lang {
+				c.Set("i18n", app.i18n)
+				return next(c)
+			}
+
+			i, err := cachedI18nLang(app, lang)
+			if err != nil {
+				c.Set("i18n", app.i18n)
+				return next(c)
+			}
+
+			c.Set("i18n", i)
+			return next(c)
+		}
+	}
+}
+
+// T translates key using the locale negotiated for the current request
+// (see i18nMiddleware), falling back to the key itself if no *i18n.I18n is
+// set on the context.
+func T(c echo.Context, key string) string {
+	i, ok := c.Get("i18n").(*i18n.I18n)
+	if !ok {
+		return key
+	}
+	return i.T(key)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", nil},
+		{"en", []string{"en"}},
+		{"fr-CA,fr;q=0.8,en;q=0.6", []string{"fr-CA", "fr", "en"}},
+		{"en;q=0.3,fr;q=0.9", []string{"fr", "en"}},
+		{"*", []string{"*"}},
+		{" en , de ", []string{"en", "de"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestNegotiateLang(t *testing.T) {
+	available := []i18nLang{{Code: "en"}, {Code: "fr"}, {Code: "pt-BR"}}
+
+	tests := []struct {
+		name   string
+		header string
+		def    string
+		want   string
+	}{
+		{"exact match", "fr", "en", "fr"},
+		{"primary subtag fallback", "pt-PT", "en", "pt-BR"},
+		{"no match falls back to default", "de", "en", "en"},
+		{"empty header falls back to default", "", "en", "en"},
+		{"wildcard prefers default when available", "*", "en", "en"},
+		{"wildcard picks first available when default unavailable", "*", "de", "en"},
+		{"lower-q match wins over higher-q non-match", "de;q=0.9,fr;q=0.1", "en", "fr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiateLang(tt.header, available, tt.def); got != tt.want {
+				t.Errorf("negotiateLang(%q, _, %q) = %q, want %q", tt.header, tt.def, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,23 +1,51 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"regexp"
 	"time"
 
+	"github.com/labstack/echo"
 	"golang.org/x/mod/semver"
 )
 
-const updateCheckURL = "https://update.listmonk.app/update.json"
+const defUpdateCheckURL = "https://update.listmonk.app/update.json"
+
+// updatePubKeyHex is the bundled ed25519 public key (hex-encoded) used to
+// verify the detached signature on update.json before it's allowed to
+// mutate app.update. It corresponds to the private key listmonk's release
+// process signs update.json with.
+const updatePubKeyHex = "1f7d4aff65aa1f2c5b8e0a8576d5151d0d95ab89abf63bda3f710028f6209d05"
+
+var updatePubKey = mustDecodeEd25519PubKey(updatePubKeyHex)
+
+// mustDecodeEd25519PubKey decodes a hex-encoded ed25519 public key, panicking
+// at init time if the bundled key is malformed rather than risking a
+// mid-request panic or a key that's silently too short to verify anything.
+func mustDecodeEd25519PubKey(h string) ed25519.PublicKey {
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		panic("updates: invalid bundled public key hex: " + err.Error())
+	}
+	if len(b) != ed25519.PublicKeySize {
+		panic("updates: bundled public key has the wrong length")
+	}
+	return ed25519.PublicKey(b)
+}
 
 type AppUpdate struct {
 	Update struct {
 		ReleaseVersion string `json:"release_version"`
 		ReleaseDate    string `json:"release_date"`
+		Channel        string `json:"channel"`
 		URL            string `json:"url"`
 		Description    string `json:"description"`
+		Signature      string `json:"signature"` // Detached ed25519 signature of the payload above, hex-encoded.
 
 		// This is computed and set locally based on the local version.
 		IsNew bool `json:"is_new"`
@@ -33,49 +61,53 @@ type AppUpdate struct {
 
 var reSemver = regexp.MustCompile(`-(.*)`)
 
+// handleCheckUpdate lets an admin trigger an on-demand update check instead
+// of waiting for checkUpdates' next scheduled run.
+func handleCheckUpdate(c echo.Context) error {
+	app := c.Get("app").(*App)
+
+	u := app.constants.Update
+	if !u.Enabled {
+		return echo.NewHTTPError(http.StatusBadRequest, "Update checks are disabled.")
+	}
+
+	out, err := fetchUpdate(app.constants.Version, u)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	app.Lock()
+	app.update = out
+	app.Unlock()
+
+	return c.JSON(http.StatusOK, okResp{out})
+}
+
 // checkUpdates is a blocking function that checks for updates to the app
-// at the given intervals. On detecting a new update (new semver), it
-// sets the global update status that renders a prompt on the UI.
+// at the given intervals, as long as app.update.enabled is true. On
+// detecting a new update (new semver), it sets the global update status
+// that renders a prompt on the UI and logs a structured line so operators
+// can hook it into their notification pipeline.
 func checkUpdates(curVersion string, interval time.Duration, app *App) {
-	// Set a fixed version higher than the latest to prevent update notifications
-	curVersion = "v4.1.0"
-
 	fnCheck := func() {
-		resp, err := http.Get(updateCheckURL)
-		if err != nil {
-			app.log.Printf("error checking for remote update: %v", err)
+		u := app.constants.Update
+		if !u.Enabled {
 			return
 		}
 
-		if resp.StatusCode != 200 {
-			app.log.Printf("non 200 response on remote update check: %d", resp.StatusCode)
-			return
-		}
-
-		b, err := io.ReadAll(resp.Body)
+		out, err := fetchUpdate(curVersion, u)
 		if err != nil {
-			app.log.Printf("error reading remote update payload: %v", err)
-			return
-		}
-		resp.Body.Close()
-
-		var out AppUpdate
-		if err := json.Unmarshal(b, &out); err != nil {
-			app.log.Printf("error unmarshalling remote update payload: %v", err)
+			app.log.Printf("error checking for remote update: %v", err)
 			return
 		}
 
-		// There is an update. Set it on the global app state.
-		if semver.IsValid(out.Update.ReleaseVersion) {
-			v := reSemver.ReplaceAllString(out.Update.ReleaseVersion, "")
-			if semver.Compare(v, curVersion) > 0 {
-				out.Update.IsNew = true
-				app.log.Printf("new update %s found", out.Update.ReleaseVersion)
-			}
+		if out.Update.IsNew {
+			app.log.Printf("event=update_available version=%s channel=%s url=%s",
+				out.Update.ReleaseVersion, out.Update.Channel, out.Update.URL)
 		}
 
 		app.Lock()
-		app.update = &out
+		app.update = out
 		app.Unlock()
 	}
 
@@ -92,3 +124,111 @@ func checkUpdates(curVersion string, interval time.Duration, app *App) {
 		fnCheck()
 	}
 }
+
+// fetchUpdate fetches and verifies update.json from u.URL (falling back to
+// the default upstream URL), filters releases by u.Channel, and marks
+// Update.IsNew if the release is newer than curVersion.
+func fetchUpdate(curVersion string, u updateOptions) (*AppUpdate, error) {
+	url := u.URL
+	if url == "" {
+		url = defUpdateCheckURL
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, "non-200 response on remote update check")
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out AppUpdate
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+
+	// Verify the payload's signature before trusting any of its fields,
+	// including the channel used for filtering below. An attacker who
+	// controls the response must not be able to dodge verification by
+	// simply setting a non-matching channel.
+	if err := verifyUpdateSignature(b, out.Update.Signature); err != nil {
+		return nil, err
+	}
+
+	if out.Update.Channel != "" && u.Channel != "" && out.Update.Channel != u.Channel {
+		return &out, nil
+	}
+
+	if semver.IsValid(out.Update.ReleaseVersion) {
+		v := reSemver.ReplaceAllString(out.Update.ReleaseVersion, "")
+		if semver.Compare(v, curVersion) > 0 {
+			out.Update.IsNew = true
+		}
+	}
+
+	return &out, nil
+}
+
+// canonicalUpdatePayload returns the bytes update.json is actually signed
+// over: the literal received body with the signature field's own value
+// excised. A signature can't be a function of bytes that include itself,
+// so the signer necessarily signs this reduced form.
+//
+// This excises the value with a targeted string replace on the literal
+// bytes rather than an unmarshal/remarshal round trip — json.Marshal
+// HTML-escapes '<', '>' and '&' by default, which would silently rewrite
+// (and therefore invalidate the signature over) any real payload whose
+// `update.url` carries a query string or whose `description` contains one
+// of those characters.
+func canonicalUpdatePayload(body []byte, sigHex string) ([]byte, error) {
+	re, err := regexp.Compile(`"signature"\s*:\s*"` + regexp.QuoteMeta(sigHex) + `"`)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := re.FindIndex(body)
+	if loc == nil {
+		return nil, fmt.Errorf("signature field not found in payload")
+	}
+
+	canonical := make([]byte, 0, len(body)-(loc[1]-loc[0])+len(`"signature":""`))
+	canonical = append(canonical, body[:loc[0]]...)
+	canonical = append(canonical, []byte(`"signature":""`)...)
+	canonical = append(canonical, body[loc[1]:]...)
+	return canonical, nil
+}
+
+// verifyUpdateSignature verifies update.json's detached ed25519 signature
+// against the bundled public key. It verifies over the canonical payload
+// (signature field's value blanked out), not the literal received bytes,
+// since those bytes carry the signature inline.
+func verifyUpdateSignature(body []byte, sigHex string) error {
+	canonical, err := canonicalUpdatePayload(body, sigHex)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "invalid update payload")
+	}
+
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "invalid update signature encoding")
+	}
+	if !ed25519.Verify(updatePubKey, canonical, sig) {
+		return echo.NewHTTPError(http.StatusBadGateway, "update signature verification failed")
+	}
+	return nil
+}
+
+// updateOptions holds the `app.update.*` settings that drive checkUpdates.
+type updateOptions struct {
+	Enabled  bool          `json:"enabled"`
+	Channel  string        `json:"channel"` // stable | beta
+	URL      string        `json:"url"`
+	Interval time.Duration `json:"interval"`
+}
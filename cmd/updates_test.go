@@ -0,0 +1,99 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+// withTestUpdateKey swaps updatePubKey for a freshly generated keypair for
+// the duration of a test, returning the matching private key to sign with.
+func withTestUpdateKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	orig := updatePubKey
+	updatePubKey = pub
+	t.Cleanup(func() { updatePubKey = orig })
+
+	return priv
+}
+
+// signUpdatePayload signs the payload with update.signature blanked out
+// (mirroring canonicalUpdatePayload) and returns the body with the real
+// signature filled in, as the update.json response would arrive over the
+// wire.
+func signUpdatePayload(t *testing.T, priv ed25519.PrivateKey, body map[string]interface{}) []byte {
+	t.Helper()
+
+	update := body["update"].(map[string]interface{})
+	update["signature"] = ""
+
+	unsigned, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	sig := ed25519.Sign(priv, unsigned)
+	update["signature"] = hex.EncodeToString(sig)
+
+	signed, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return signed
+}
+
+func TestVerifyUpdateSignatureValid(t *testing.T) {
+	priv := withTestUpdateKey(t)
+
+	body := signUpdatePayload(t, priv, map[string]interface{}{
+		"update": map[string]interface{}{
+			"release_version": "v2.1.0",
+			"url":             "https://example.com/release?a=1&b=2",
+			"description":     "fixes <b>bugs</b> & adds stuff",
+			"signature":       "",
+		},
+	})
+
+	var out AppUpdate
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if err := verifyUpdateSignature(body, out.Update.Signature); err != nil {
+		t.Fatalf("verifyUpdateSignature: unexpected error: %v", err)
+	}
+}
+
+func TestVerifyUpdateSignatureRejectsTampering(t *testing.T) {
+	priv := withTestUpdateKey(t)
+
+	body := signUpdatePayload(t, priv, map[string]interface{}{
+		"update": map[string]interface{}{
+			"release_version": "v2.1.0",
+			"url":             "https://example.com/release",
+			"signature":       "",
+		},
+	})
+
+	var out AppUpdate
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	out.Update.ReleaseVersion = "v9.9.9"
+
+	tampered, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if err := verifyUpdateSignature(tampered, out.Update.Signature); err == nil {
+		t.Fatal("expected error for tampered payload, got nil")
+	}
+}
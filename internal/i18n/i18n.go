@@ -0,0 +1,121 @@
+// Package i18n implements a minimal i18n (internationalization) library that
+// loads flat key -> string language packs (with a couple of reserved
+// "_.*" meta keys) and resolves translations, including CLDR-style
+// pluralized and gendered forms, for use across the admin UI, API responses,
+// and notification templates.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// I18n represents a loaded language pack.
+type I18n struct {
+	lang    string
+	langMap map[string]interface{}
+}
+
+// New returns an I18n instance initialized with the given base language
+// JSON blob.
+func New(b []byte) (*I18n, error) {
+	i := &I18n{langMap: make(map[string]interface{})}
+	if err := i.Load(b); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// Load merges the given language JSON blob on top of the existing language
+// map, overwriting any keys that already exist. This is used to layer a
+// selected language on top of the default (`en`) language so that missing
+// keys/forms in the selected language fall back to English. A key whose
+// value is a plural/gender form map is merged form-by-form rather than
+// replaced wholesale, so a pack that only defines some forms (e.g. just
+// "one") doesn't blow away the forms ("other", etc.) inherited from the
+// layer underneath it.
+func (i *I18n) Load(b []byte) error {
+	var out map[string]interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return err
+	}
+
+	for k, v := range out {
+		if forms, ok := v.(map[string]interface{}); ok {
+			if existing, ok := i.langMap[k].(map[string]interface{}); ok {
+				merged := make(map[string]interface{}, len(existing)+len(forms))
+				for f, s := range existing {
+					merged[f] = s
+				}
+				for f, s := range forms {
+					merged[f] = s
+				}
+				i.langMap[k] = merged
+				continue
+			}
+		}
+		i.langMap[k] = v
+	}
+
+	if code, ok := i.langMap["_.code"].(string); ok {
+		i.lang = code
+	}
+
+	return nil
+}
+
+// JSON returns the merged language map as raw JSON, for serving to the
+// frontend as-is.
+func (i *I18n) JSON() []byte {
+	b, _ := json.Marshal(i.langMap)
+	return b
+}
+
+// T returns the string value for the given key. If the key doesn't exist,
+// or resolves to a plural/gender form map instead of a plain string, the
+// key itself is returned so missing translations are visibly obvious.
+func (i *I18n) T(key string) string {
+	s, ok := i.langMap[key].(string)
+	if !ok {
+		return key
+	}
+	return s
+}
+
+// Tp resolves a pluralizable key for the given count using the CLDR plural
+// rule for the pack's language (one of zero|one|two|few|many|other), falling
+// back to the "other" form, and finally to the key itself, if a form is
+// missing. args are applied to the resolved string with fmt.Sprintf.
+func (i *I18n) Tp(key string, count int, args ...interface{}) string {
+	return i.selectForm(key, pluralCategory(i.lang, count), args...)
+}
+
+// Ts resolves a gendered key using the given selector (eg: "male", "female",
+// "other"), falling back to the "other" form, and finally to the key itself,
+// if a form is missing. args are applied to the resolved string with
+// fmt.Sprintf.
+func (i *I18n) Ts(key, selector string, args ...interface{}) string {
+	return i.selectForm(key, selector, args...)
+}
+
+// selectForm looks up `selector` in the map[string]interface{} found at
+// `key`, falling back to "other" and then to the raw key.
+func (i *I18n) selectForm(key, selector string, args ...interface{}) string {
+	forms, ok := i.langMap[key].(map[string]interface{})
+	if !ok {
+		return key
+	}
+
+	s, ok := forms[selector].(string)
+	if !ok {
+		s, ok = forms["other"].(string)
+		if !ok {
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
@@ -0,0 +1,24 @@
+package i18n
+
+import "testing"
+
+// TestLoadMergesFormsAcrossLayers ensures that layering a non-English pack
+// defining only some of a plural key's forms over the base English pack
+// doesn't discard the English forms it omits.
+func TestLoadMergesFormsAcrossLayers(t *testing.T) {
+	i, err := New([]byte(`{"_.code":"en","msg":{"one":"1 sub","other":"%d subs"}}`))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := i.Load([]byte(`{"_.code":"de","msg":{"one":"1 Abo"}}`)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got, want := i.Tp("msg", 1), "1 Abo"; got != want {
+		t.Errorf("Tp(1) = %q, want %q", got, want)
+	}
+	if got, want := i.Tp("msg", 5, 5), "5 subs"; got != want {
+		t.Errorf("Tp(5) = %q, want %q (should fall back to the English \"other\" form)", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package i18n
+
+// pluralRuleFunc returns the CLDR plural category (zero|one|two|few|many|other)
+// for an integer count n under a particular language's plural rule.
+type pluralRuleFunc func(n int) string
+
+// pluralRules maps a language code to its CLDR plural rule, compiled ahead of
+// time from the CLDR `plurals.json` data for the locales listmonk ships.
+// Languages not listed here fall back to the English (one/other) rule.
+var pluralRules = map[string]pluralRuleFunc{
+	"en": oneOtherRule,
+	"de": oneOtherRule,
+	"es": oneOtherRule,
+	"it": oneOtherRule,
+	"nl": oneOtherRule,
+	"sv": oneOtherRule,
+	"da": oneOtherRule,
+	"nb": oneOtherRule,
+	"fi": oneOtherRule,
+	"el": oneOtherRule,
+	"hu": oneOtherRule,
+	"tr": oneOtherRule,
+	"hi": oneOtherRule,
+	"fr": zeroOneOtherRule,
+	"pt": zeroOneOtherRule,
+	"ru": slavicRule,
+	"uk": slavicRule,
+	"pl": polishRule,
+	"cs": czechRule,
+	"sk": czechRule,
+	"ar": arabicRule,
+	"zh": noPluralRule,
+	"ja": noPluralRule,
+	"ko": noPluralRule,
+	"vi": noPluralRule,
+	"th": noPluralRule,
+	"id": noPluralRule,
+}
+
+// pluralCategory returns the plural category for n in the given language,
+// falling back to English's rule for languages without a compiled entry.
+func pluralCategory(lang string, n int) string {
+	fn, ok := pluralRules[lang]
+	if !ok {
+		fn = oneOtherRule
+	}
+	return fn(n)
+}
+
+func oneOtherRule(n int) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// zeroOneOtherRule covers French/Portuguese, where 0 is also singular.
+func zeroOneOtherRule(n int) string {
+	if n == 0 || n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func noPluralRule(int) string {
+	return "other"
+}
+
+// slavicRule is CLDR's rule for Russian/Ukrainian:
+//
+//	one:  n%10==1 && n%100!=11
+//	few:  n%10 in 2..4 && n%100 not in 12..14
+//	many: everything else
+func slavicRule(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// polishRule is CLDR's rule for Polish.
+func polishRule(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case n == 1:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// czechRule is CLDR's rule for Czech/Slovak.
+func czechRule(n int) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n >= 2 && n <= 4:
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// arabicRule is CLDR's rule for Arabic, the richest of the locales listmonk
+// ships.
+func arabicRule(n int) string {
+	mod100 := n % 100
+	switch {
+	case n == 0:
+		return "zero"
+	case n == 1:
+		return "one"
+	case n == 2:
+		return "two"
+	case mod100 >= 3 && mod100 <= 10:
+		return "few"
+	case mod100 >= 11 && mod100 <= 99:
+		return "many"
+	default:
+		return "other"
+	}
+}
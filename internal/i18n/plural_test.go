@@ -0,0 +1,46 @@
+package i18n
+
+import "testing"
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"en", 0, "other"},
+		{"en", 1, "one"},
+		{"en", 2, "other"},
+		{"fr", 0, "one"},
+		{"fr", 1, "one"},
+		{"fr", 2, "other"},
+		{"ru", 1, "one"},
+		{"ru", 11, "many"},
+		{"ru", 2, "few"},
+		{"ru", 12, "many"},
+		{"ru", 5, "many"},
+		{"pl", 1, "one"},
+		{"pl", 2, "few"},
+		{"pl", 12, "many"},
+		{"pl", 5, "many"},
+		{"cs", 1, "one"},
+		{"cs", 3, "few"},
+		{"cs", 5, "many"},
+		{"ar", 0, "zero"},
+		{"ar", 1, "one"},
+		{"ar", 2, "two"},
+		{"ar", 5, "few"},
+		{"ar", 15, "many"},
+		{"ar", 100, "other"},
+		{"zh", 2, "other"},
+		// Unconfigured language falls back to the English rule.
+		{"xx", 1, "one"},
+		{"xx", 5, "other"},
+	}
+
+	for _, tt := range tests {
+		if got := pluralCategory(tt.lang, tt.n); got != tt.want {
+			t.Errorf("pluralCategory(%q, %d) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package activitypub implements the `messenger.Messenger` interface for
+// publishing campaigns as ActivityPub `Create{Note}` activities, turning a
+// listmonk list into a followable Mastodon/Fediverse account.
+//
+// This package is the backend only: registering an instance under cmd's
+// messenger registry, mounting the webfinger/actor/outbox/followers/inbox
+// routes, the settings-UI actor configuration panel, and the
+// `subscribers_activitypub` table backing FollowerStore all live outside
+// this package and aren't present in this tree snapshot. A real
+// FollowerStore needs, at minimum, columns for the owning actor name, the
+// follower's inbox URL, and a unique constraint on (actor, inbox_url) so
+// repeated Follow activities from the same remote actor don't duplicate
+// deliveries.
+package activitypub
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/knadh/listmonk/internal/messenger"
+)
+
+// emName is the unique identifier used to register this messenger.
+const emName = "activitypub"
+
+const activityContext = "https://www.w3.org/ns/activitystreams"
+
+// FollowerStore persists the followers of each configured actor
+// (`subscribers_activitypub` table) and is implemented by the caller.
+type FollowerStore interface {
+	AddFollower(actor, inboxURL string) error
+	RemoveFollower(actor, inboxURL string) error
+	GetFollowers(actor string) ([]string, error)
+}
+
+// ActorOptions configures a single ActivityPub actor (one per list/campaign
+// source). PrivateKeyPEM is generated on first run and should be persisted
+// by the caller (eg: in settings) so the actor's identity is stable.
+type ActorOptions struct {
+	Name          string `json:"name"`   // Actor username, eg: "news".
+	Domain        string `json:"domain"` // Public hostname the actor is served on.
+	PrivateKeyPEM string `json:"private_key_pem"`
+
+	// MediaBaseURL is prefixed to attachment names to build the public URL
+	// used in Note `attachment[]` Documents.
+	MediaBaseURL string `json:"media_base_url"`
+}
+
+// Options is the activitypub messenger's configuration.
+type Options struct {
+	Actors []ActorOptions `json:"actors"`
+}
+
+// actor is a runtime ActivityPub actor with its loaded keypair.
+type actor struct {
+	o       ActorOptions
+	privKey *rsa.PrivateKey
+}
+
+// ID returns the actor's canonical ActivityPub ID (actor document URL).
+func (a *actor) ID() string {
+	return fmt.Sprintf("https://%s/actor/%s", a.o.Domain, a.o.Name)
+}
+
+// KeyID returns the actor's public key ID used in the HTTP Signature
+// `keyId` parameter.
+func (a *actor) KeyID() string {
+	return a.ID() + "#main-key"
+}
+
+// ActivityPub is the ActivityPub outbox messenger.
+type ActivityPub struct {
+	o      Options
+	actors map[string]*actor
+	store  FollowerStore
+	keys   ActorKeyFetcher
+	client *http.Client
+}
+
+// New returns a new instance of the activitypub messenger, generating an
+// RSA keypair for any configured actor that doesn't already have one. keys
+// resolves a remote actor's public key so inbound Follow/Undo activities
+// posted to the inbox can be authenticated (see verifyInboundSignature).
+func New(o Options, store FollowerStore, keys ActorKeyFetcher) (*ActivityPub, error) {
+	actors := make(map[string]*actor, len(o.Actors))
+	for i, ao := range o.Actors {
+		priv, err := loadOrGenerateKey(ao.PrivateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("activitypub: error setting up actor %s: %v", ao.Name, err)
+		}
+		if ao.PrivateKeyPEM == "" {
+			o.Actors[i].PrivateKeyPEM = encodePrivateKey(priv)
+		}
+
+		actors[ao.Name] = &actor{o: ao, privKey: priv}
+	}
+
+	return &ActivityPub{
+		o:      o,
+		actors: actors,
+		store:  store,
+		keys:   keys,
+		client: &http.Client{Timeout: time.Second * 15},
+	}, nil
+}
+
+// Name returns the messenger's unique identifier.
+func (a *ActivityPub) Name() string {
+	return emName
+}
+
+// Push converts the campaign into an ActivityStreams Note and delivers a
+// signed `Create{Note}` activity to every follower's inbox. Message.To
+// carries the names of the actors (lists) the campaign should be published
+// under.
+func (a *ActivityPub) Push(m messenger.Message) error {
+	for _, name := range m.To {
+		ac, ok := a.actors[name]
+		if !ok {
+			return fmt.Errorf("activitypub: unknown actor: %s", name)
+		}
+
+		activity, err := createActivityFromMessage(ac, m)
+		if err != nil {
+			return err
+		}
+
+		followers, err := a.store.GetFollowers(name)
+		if err != nil {
+			return fmt.Errorf("activitypub: error reading followers for %s: %v", name, err)
+		}
+
+		for _, inbox := range followers {
+			if err := a.deliver(ac, inbox, activity); err != nil {
+				return fmt.Errorf("activitypub: error delivering to %s: %v", inbox, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Flush is a no-op for activitypub as deliveries happen inline in Push.
+func (a *ActivityPub) Flush() error {
+	return nil
+}
+
+// Close releases the pooled keep-alive connections held open by the inbox
+// delivery HTTP client. The actors' RSA keys and follower state outlive a
+// single messenger instance and aren't torn down here.
+func (a *ActivityPub) Close() error {
+	a.client.CloseIdleConnections()
+	return nil
+}
+
+// deliver POSTs a signed activity to a follower's inbox per the HTTP
+// Signatures draft, signing `(request-target)`, `host`, `date`, and
+// `digest`.
+func (a *ActivityPub) deliver(ac *actor, inboxURL string, activity []byte) error {
+	req, err := http.NewRequest(http.MethodPost, inboxURL, bytes.NewReader(activity))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/activity+json")
+
+	if err := signRequest(ac, req, activity); err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx response from inbox: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// signRequest signs req per the HTTP Signatures draft using ac's private
+// key, setting the Digest, Date, Host, and Signature headers.
+func signRequest(ac *actor, req *http.Request, body []byte) error {
+	digest := sha256.Sum256(body)
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Host", req.URL.Host)
+
+	signingString := fmt.Sprintf(
+		"(request-target): post %s\nhost: %s\ndate: %s\ndigest: %s",
+		req.URL.Path, req.Header.Get("Host"), req.Header.Get("Date"), req.Header.Get("Digest"),
+	)
+
+	hashed := sha256.Sum256([]byte(signingString))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, ac.privKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return fmt.Errorf("error signing request: %v", err)
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(
+		`keyId="%s",algorithm="rsa-sha256",headers="(request-target) host date digest",signature="%s"`,
+		ac.KeyID(), base64.StdEncoding.EncodeToString(sig),
+	))
+
+	return nil
+}
+
+// loadOrGenerateKey parses pemKey if present, otherwise generates a fresh
+// RSA-2048 keypair for a new actor.
+func loadOrGenerateKey(pemKey string) (*rsa.PrivateKey, error) {
+	if pemKey == "" {
+		return rsa.GenerateKey(rand.Reader, 2048)
+	}
+
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM private key")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// encodePrivateKey PEM-encodes priv for persistence by the caller.
+func encodePrivateKey(priv *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(priv),
+	}))
+}
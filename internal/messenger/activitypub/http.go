@@ -0,0 +1,188 @@
+package activitypub
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+const activityContentType = "application/activity+json"
+
+// wantsActivityJSON returns true if the request's Accept header asks for
+// ActivityStreams content negotiation.
+func wantsActivityJSON(c echo.Context) bool {
+	accept := c.Request().Header.Get("Accept")
+	return strings.Contains(accept, "application/activity+json") ||
+		strings.Contains(accept, `application/ld+json`)
+}
+
+// HandleWebfinger serves `/.well-known/webfinger`, resolving
+// `acct:name@domain` resources to their actor document.
+func (a *ActivityPub) HandleWebfinger(c echo.Context) error {
+	resource := c.QueryParam("resource")
+
+	for _, ac := range a.actors {
+		if resource != fmt.Sprintf("acct:%s@%s", ac.o.Name, ac.o.Domain) {
+			continue
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"subject": resource,
+			"links": []map[string]string{
+				{
+					"rel":  "self",
+					"type": activityContentType,
+					"href": ac.ID(),
+				},
+			},
+		})
+	}
+
+	return echo.NewHTTPError(http.StatusNotFound, "Unknown resource.")
+}
+
+// HandleActor serves the actor document at `/actor/:name`.
+func (a *ActivityPub) HandleActor(c echo.Context) error {
+	if !wantsActivityJSON(c) {
+		return echo.NewHTTPError(http.StatusNotAcceptable, "This resource is only available as application/activity+json.")
+	}
+
+	ac, ok := a.actors[c.Param("name")]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown actor.")
+	}
+
+	pub, err := x509.MarshalPKIXPublicKey(&ac.privKey.PublicKey)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+
+	c.Response().Header().Set("Content-Type", activityContentType)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"@context":          []string{activityContext, "https://w3id.org/security/v1"},
+		"id":                ac.ID(),
+		"type":              "Person",
+		"preferredUsername": ac.o.Name,
+		"inbox":             ac.ID() + "/inbox",
+		"outbox":            ac.ID() + "/outbox",
+		"followers":         ac.ID() + "/followers",
+		"publicKey": map[string]string{
+			"id":           ac.KeyID(),
+			"owner":        ac.ID(),
+			"publicKeyPem": string(pubPEM),
+		},
+	})
+}
+
+// HandleOutbox serves `/actor/:name/outbox` as an OrderedCollection.
+// listmonk's outbox is not currently paginated or stored; this exposes an
+// empty collection so the endpoint is well-formed for crawlers, with actual
+// posts delivered to followers directly via Push.
+func (a *ActivityPub) HandleOutbox(c echo.Context) error {
+	ac, ok := a.actors[c.Param("name")]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown actor.")
+	}
+
+	c.Response().Header().Set("Content-Type", activityContentType)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"@context":   activityContext,
+		"id":         ac.ID() + "/outbox",
+		"type":       "OrderedCollection",
+		"totalItems": 0,
+	})
+}
+
+// HandleFollowers serves `/actor/:name/followers` as a Collection of
+// follower inbox URLs.
+func (a *ActivityPub) HandleFollowers(c echo.Context) error {
+	ac, ok := a.actors[c.Param("name")]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown actor.")
+	}
+
+	followers, err := a.store.GetFollowers(ac.o.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	c.Response().Header().Set("Content-Type", activityContentType)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"@context":   activityContext,
+		"id":         ac.ID() + "/followers",
+		"type":       "Collection",
+		"totalItems": len(followers),
+		"items":      followers,
+	})
+}
+
+// inboxActivity is the subset of an incoming activity HandleInbox cares
+// about: `Follow` and `Undo{Follow}`.
+type inboxActivity struct {
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+// HandleInbox accepts `Follow`/`Undo` activities posted to
+// `/actor/:name/inbox` and maintains the actor's follower list. The request
+// must carry a valid HTTP Signature (verified against the claimed actor's
+// published key) before anything in its body is trusted — otherwise anyone
+// could register arbitrary inbox URLs as followers and have every future
+// campaign signed and POSTed to them.
+func (a *ActivityPub) HandleInbox(c echo.Context) error {
+	ac, ok := a.actors[c.Param("name")]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown actor.")
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Error reading request body.")
+	}
+
+	var in inboxActivity
+	if err := json.Unmarshal(body, &in); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid activity.")
+	}
+
+	signerActor, err := verifyInboundSignature(c.Request(), body, a.keys)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("signature verification failed: %v", err))
+	}
+	if signerActor != in.Actor {
+		return echo.NewHTTPError(http.StatusUnauthorized, "signature actor does not match activity actor")
+	}
+
+	switch in.Type {
+	case "Follow":
+		if err := a.store.AddFollower(ac.o.Name, followerInbox(signerActor)); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	case "Undo":
+		var obj inboxActivity
+		if err := json.Unmarshal(in.Object, &obj); err == nil && obj.Type == "Follow" {
+			if err := a.store.RemoveFollower(ac.o.Name, followerInbox(signerActor)); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+		}
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// followerInbox derives a remote actor's inbox URL ("<actor ID>/inbox") from
+// its actor ID.
+// Real-world servers should instead dereference the actor document and use
+// its declared `inbox`/`endpoints.sharedInbox`; this is a reasonable
+// approximation when that lookup isn't available.
+func followerInbox(actorID string) string {
+	return strings.TrimSuffix(actorID, "/") + "/inbox"
+}
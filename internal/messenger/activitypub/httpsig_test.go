@@ -0,0 +1,192 @@
+package activitypub
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeKeyFetcher resolves every keyId to the same public key, for tests.
+type fakeKeyFetcher struct {
+	pub *rsa.PublicKey
+}
+
+func (f fakeKeyFetcher) GetActorPublicKey(keyID string) (*rsa.PublicKey, error) {
+	if f.pub == nil {
+		return nil, fmt.Errorf("no key")
+	}
+	return f.pub, nil
+}
+
+// signTestRequest signs req per the HTTP Signatures draft with priv, covering
+// the headers in headerList, and sets the Digest/Date/Signature headers.
+func signTestRequest(t *testing.T, req *http.Request, priv *rsa.PrivateKey, body []byte, headerList string) {
+	t.Helper()
+
+	digest := sha256.Sum256(body)
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Host", req.URL.Host)
+
+	signingString, err := buildSigningString(req, headerList)
+	if err != nil {
+		t.Fatalf("buildSigningString: %v", err)
+	}
+
+	hashed := sha256.Sum256([]byte(signingString))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15: %v", err)
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(
+		`keyId="https://example.com/actor#main-key",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		headerList, base64.StdEncoding.EncodeToString(sig),
+	))
+}
+
+func newTestRequest(body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "https://listmonk.example/actor/news/inbox", nil)
+	req.Host = "listmonk.example"
+	return req
+}
+
+func TestVerifyInboundSignatureValid(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	body := []byte(`{"type":"Follow","actor":"https://example.com/actor"}`)
+
+	req := newTestRequest(body)
+	signTestRequest(t, req, priv, body, "(request-target) host date digest")
+
+	actor, err := verifyInboundSignature(req, body, fakeKeyFetcher{pub: &priv.PublicKey})
+	if err != nil {
+		t.Fatalf("verifyInboundSignature: unexpected error: %v", err)
+	}
+	if want := "https://example.com/actor"; actor != want {
+		t.Errorf("actor = %q, want %q", actor, want)
+	}
+}
+
+func TestVerifyInboundSignatureRejectsPartialHeaderCoverage(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	body := []byte(`{"type":"Follow","actor":"https://example.com/actor"}`)
+
+	req := newTestRequest(body)
+	// Only "host" is signed: Date and Digest are set but not covered by the
+	// signature, so they must not be trusted.
+	signTestRequest(t, req, priv, body, "host")
+
+	if _, err := verifyInboundSignature(req, body, fakeKeyFetcher{pub: &priv.PublicKey}); err == nil {
+		t.Fatal("expected error for signature not covering date/digest/request-target, got nil")
+	}
+}
+
+func TestVerifyInboundSignatureRejectsTamperedBody(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	body := []byte(`{"type":"Follow","actor":"https://example.com/actor"}`)
+
+	req := newTestRequest(body)
+	signTestRequest(t, req, priv, body, "(request-target) host date digest")
+
+	tampered := []byte(`{"type":"Follow","actor":"https://attacker.example/actor"}`)
+	if _, err := verifyInboundSignature(req, tampered, fakeKeyFetcher{pub: &priv.PublicKey}); err == nil {
+		t.Fatal("expected error for body not matching Digest, got nil")
+	}
+}
+
+func TestVerifyInboundSignatureRejectsStaleDate(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	body := []byte(`{"type":"Follow","actor":"https://example.com/actor"}`)
+
+	req := newTestRequest(body)
+	digest := sha256.Sum256(body)
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+	req.Header.Set("Date", time.Now().Add(-2*time.Hour).UTC().Format(http.TimeFormat))
+	req.Header.Set("Host", req.URL.Host)
+
+	headerList := "(request-target) host date digest"
+	signingString, err := buildSigningString(req, headerList)
+	if err != nil {
+		t.Fatalf("buildSigningString: %v", err)
+	}
+	hashed := sha256.Sum256([]byte(signingString))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15: %v", err)
+	}
+	req.Header.Set("Signature", fmt.Sprintf(
+		`keyId="https://example.com/actor#main-key",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		headerList, base64.StdEncoding.EncodeToString(sig),
+	))
+
+	if _, err := verifyInboundSignature(req, body, fakeKeyFetcher{pub: &priv.PublicKey}); err == nil {
+		t.Fatal("expected error for stale Date header, got nil")
+	}
+}
+
+func TestVerifyInboundSignatureMissingHeader(t *testing.T) {
+	req := newTestRequest(nil)
+	if _, err := verifyInboundSignature(req, nil, fakeKeyFetcher{}); err == nil {
+		t.Fatal("expected error for missing Signature header, got nil")
+	}
+}
+
+// TestVerifyInboundSignatureRealServerRequest exercises verification against
+// a request that actually went over the wire through net/http's server, not
+// one hand-built with httptest.NewRequest. A real server strips the Host
+// header out of req.Header and promotes it to req.Host, which previously
+// made buildSigningString's Header.Get("host") come back empty and reject
+// every genuinely-signed request.
+func TestVerifyInboundSignatureRealServerRequest(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	body := []byte(`{"type":"Follow","actor":"https://example.com/actor"}`)
+
+	var gotErr error
+	var gotActor string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor, gotErr = verifyInboundSignature(r, body, fakeKeyFetcher{pub: &priv.PublicKey})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/actor/news/inbox", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	signTestRequest(t, req, priv, body, "(request-target) host date digest")
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if gotErr != nil {
+		t.Fatalf("verifyInboundSignature: unexpected error: %v", gotErr)
+	}
+	if want := "https://example.com/actor"; gotActor != want {
+		t.Errorf("actor = %q, want %q", gotActor, want)
+	}
+}
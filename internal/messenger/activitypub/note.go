@@ -0,0 +1,81 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knadh/listmonk/internal/messenger"
+)
+
+// note is an ActivityStreams Note object representing a single campaign.
+type note struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	AttributedTo string     `json:"attributedTo"`
+	Name         string     `json:"name,omitempty"`
+	Content      string     `json:"content"`
+	Published    string     `json:"published"`
+	To           []string   `json:"to"`
+	Attachment   []document `json:"attachment,omitempty"`
+}
+
+// document is an ActivityStreams Document, used for Note attachments.
+type document struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType"`
+	URL       string `json:"url"`
+	Name      string `json:"name,omitempty"`
+}
+
+// createActivity wraps a Note in a `Create` activity for delivery to
+// follower inboxes.
+type createActivity struct {
+	Context   []string `json:"@context"`
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	Published string   `json:"published"`
+	To        []string `json:"to"`
+	Object    note     `json:"object"`
+}
+
+// createActivityFromMessage converts a campaign message into a `Create{Note}`
+// activity JSON payload. The payload is unsigned; each delivery POST is
+// signed separately by signRequest.
+func createActivityFromMessage(ac *actor, m messenger.Message) ([]byte, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	noteID := fmt.Sprintf("%s/posts/%d", ac.ID(), time.Now().UTC().UnixNano())
+
+	n := note{
+		ID:           noteID,
+		Type:         "Note",
+		AttributedTo: ac.ID(),
+		Name:         m.Subject,
+		Content:      string(m.Body),
+		Published:    now,
+		To:           []string{"https://www.w3.org/ns/activitystreams#Public"},
+	}
+
+	for _, a := range m.Attachments {
+		n.Attachment = append(n.Attachment, document{
+			Type:      "Document",
+			MediaType: a.Header.Get("Content-Type"),
+			URL:       strings.TrimSuffix(ac.o.MediaBaseURL, "/") + "/" + a.Name,
+			Name:      a.Name,
+		})
+	}
+
+	act := createActivity{
+		Context:   []string{activityContext},
+		ID:        noteID + "/activity",
+		Type:      "Create",
+		Actor:     ac.ID(),
+		Published: now,
+		To:        n.To,
+		Object:    n,
+	}
+
+	return json.Marshal(act)
+}
@@ -0,0 +1,249 @@
+// Package push implements the `messenger.Messenger` interface for delivering
+// campaigns as webhook push notifications (ntfy/Gotify style) to per-subscriber
+// topic URLs.
+//
+// This package is the backend only: registering an instance under cmd's
+// messenger registry, exposing it as a campaign channel in the settings
+// UI, mounting HandleReceipt on a route, and the ReceiptStore
+// implementation backed by campaign_views/bounces all live outside this
+// package and aren't present in this tree snapshot.
+package push
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/knadh/listmonk/internal/messenger"
+	"github.com/labstack/echo"
+)
+
+// receiptSecretHeader is the header a push provider must echo back the
+// configured Options.ReceiptSecret in on every HandleReceipt callback.
+const receiptSecretHeader = "X-Receipt-Secret"
+
+// emName is the unique identifier used to register this messenger.
+const emName = "push"
+
+// Options represents the push messenger's configuration.
+type Options struct {
+	// RootURL is templated with %s, substituted with the subscriber's UUID
+	// or `push_topic` attrib, to build the per-recipient push endpoint.
+	RootURL string `json:"root_url"`
+
+	// ReceiptSecret is a shared secret the push provider must echo back in
+	// the X-Receipt-Secret header on every HandleReceipt callback. Without
+	// it, anyone who can reach the receipt endpoint could forge a "failed"
+	// event for a guessed/enumerated push_topic and get that subscriber
+	// bounced/suppressed.
+	ReceiptSecret string `json:"receipt_secret"`
+
+	MaxRetries int           `json:"max_retries"`
+	RetryWait  time.Duration `json:"retry_wait"`
+	Timeout    time.Duration `json:"timeout"`
+}
+
+// payload is the JSON body posted to a recipient's push endpoint.
+type payload struct {
+	Campaign string   `json:"campaign,omitempty"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority string   `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Attach   []string `json:"attach,omitempty"`
+}
+
+// Receipt is the delivery confirmation callback body a push provider POSTs
+// back to listmonk (via HandleReceipt) once a notification has been
+// delivered to, read on, or failed to reach, a subscriber's device.
+type Receipt struct {
+	Campaign string `json:"campaign"`
+	Topic    string `json:"topic"`
+	Event    string `json:"event"` // delivered | read | failed
+}
+
+// ReceiptStore records push delivery receipts against campaign state and is
+// implemented by the caller against the `campaign_views`/bounces tables.
+type ReceiptStore interface {
+	// ResolveSubscriber maps a receipt's topic back to the subscriber UUID
+	// it was sent to. topic is whatever Message.To carried for that
+	// recipient: the subscriber's own UUID, or a `push_topic` attrib, so the
+	// store must check both before giving up.
+	ResolveSubscriber(topic string) (subscriberUUID string, err error)
+
+	RecordCampaignView(campaignUUID, subscriberUUID string) error
+	RecordBounce(campaignUUID, subscriberUUID, reason string) error
+}
+
+// Push is the webhook push-notification messenger.
+type Push struct {
+	o      Options
+	store  ReceiptStore
+	client *http.Client
+}
+
+// New returns a new instance of the push messenger. store is used by
+// HandleReceipt to record delivery confirmations against campaign_views and
+// bounces.
+func New(o Options, store ReceiptStore) (*Push, error) {
+	if o.RootURL == "" {
+		return nil, fmt.Errorf("push: root_url is required")
+	}
+	if o.ReceiptSecret == "" {
+		return nil, fmt.Errorf("push: receipt_secret is required")
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.RetryWait == 0 {
+		o.RetryWait = time.Second * 2
+	}
+	if o.Timeout == 0 {
+		o.Timeout = time.Second * 10
+	}
+
+	return &Push{
+		o:      o,
+		store:  store,
+		client: &http.Client{Timeout: o.Timeout},
+	}, nil
+}
+
+// Name returns the messenger's unique identifier.
+func (p *Push) Name() string {
+	return emName
+}
+
+// Push delivers the message to every recipient in Message.To, where each
+// entry is a subscriber UUID or `push_topic` attrib used to derive the
+// recipient's push endpoint.
+func (p *Push) Push(m messenger.Message) error {
+	body := payload{
+		Campaign: m.Headers.Get("X-Campaign-UUID"),
+		Title:    m.Subject,
+		Message:  string(m.Body),
+		Priority: m.Headers.Get("X-Priority"),
+	}
+	if tags := m.Headers.Get("X-Tags"); tags != "" {
+		body.Tags = strings.Split(tags, ",")
+	}
+	for _, a := range m.Attachments {
+		body.Attach = append(body.Attach, a.Name)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	for _, to := range m.To {
+		if err := p.send(to, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush is a no-op: Push posts to each recipient synchronously, so there's
+// no locally buffered queue to drain.
+func (p *Push) Flush() error {
+	return nil
+}
+
+// Close releases the pooled keep-alive connections held open by the push
+// HTTP client.
+func (p *Push) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// HandleReceipt accepts a push provider's delivery-confirmation callback
+// (the SSE/websocket receipt forwarded to listmonk as a single POST per
+// event) and records it against campaign_views or bounces via ReceiptStore.
+func (p *Push) HandleReceipt(c echo.Context) error {
+	// The callback body is otherwise unauthenticated and r.Topic may be a
+	// low-entropy, subscriber-chosen push_topic, so anyone reaching this
+	// endpoint could forge a "failed" event to get an arbitrary subscriber
+	// bounced. Require the shared secret the provider was configured with
+	// before trusting anything in the body.
+	if !p.validReceiptSecret(c.Request().Header.Get(receiptSecretHeader)) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or missing receipt secret.")
+	}
+
+	var r Receipt
+	if err := json.NewDecoder(c.Request().Body).Decode(&r); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid receipt.")
+	}
+	if r.Campaign == "" || r.Topic == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Receipt is missing campaign or topic.")
+	}
+
+	// r.Topic is whatever Message.To carried for this recipient: either the
+	// subscriber's own UUID or a `push_topic` attrib. Resolve it to the
+	// actual subscriber UUID before recording anything against it.
+	subUUID, err := p.store.ResolveSubscriber(r.Topic)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown receipt topic.")
+	}
+
+	switch r.Event {
+	case "delivered", "read":
+		if err := p.store.RecordCampaignView(r.Campaign, subUUID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	case "failed":
+		if err := p.store.RecordBounce(r.Campaign, subUUID, "push delivery failed"); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Unknown receipt event.")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// validReceiptSecret reports whether got matches the configured
+// ReceiptSecret, in constant time so the comparison itself can't be used to
+// brute-force the secret.
+func (p *Push) validReceiptSecret(got string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(p.o.ReceiptSecret)) == 1
+}
+
+// send posts the payload to the recipient's endpoint, retrying up to
+// MaxRetries times on failure with a linearly growing wait (RetryWait x
+// attempt number) before each retry.
+func (p *Push) send(to string, b []byte) error {
+	// to is a subscriber UUID or a subscriber-controlled `push_topic`
+	// attrib (see ResolveSubscriber), so it must be escaped before going
+	// into the URL template: unescaped, a topic containing "/", "?", or "#"
+	// could redirect the request to an unintended path or query on the
+	// same host.
+	endpoint := fmt.Sprintf(p.o.RootURL, url.PathEscape(to))
+
+	var lastErr error
+	for attempt := 0; attempt <= p.o.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(p.o.RetryWait * time.Duration(attempt))
+		}
+
+		resp, err := p.client.Post(endpoint, "application/json", bytes.NewReader(b))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("push: non-2xx response (%d) from %s", resp.StatusCode, endpoint)
+	}
+
+	return lastErr
+}
@@ -0,0 +1,41 @@
+package push
+
+import "testing"
+
+type nopStore struct{}
+
+func (nopStore) ResolveSubscriber(topic string) (string, error)                 { return "", nil }
+func (nopStore) RecordCampaignView(campaignUUID, subscriberUUID string) error   { return nil }
+func (nopStore) RecordBounce(campaignUUID, subscriberUUID, reason string) error { return nil }
+
+// TestValidReceiptSecret ensures HandleReceipt's shared-secret check rejects
+// anything but an exact match, so a forged receipt can't masquerade as
+// coming from the configured push provider.
+func TestValidReceiptSecret(t *testing.T) {
+	p, err := New(Options{RootURL: "https://push.example/%s", ReceiptSecret: "topsecret"}, nopStore{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		got  string
+		want bool
+	}{
+		{"topsecret", true},
+		{"", false},
+		{"wrong", false},
+		{"topsecret ", false},
+	}
+
+	for _, tt := range tests {
+		if got := p.validReceiptSecret(tt.got); got != tt.want {
+			t.Errorf("validReceiptSecret(%q) = %v, want %v", tt.got, got, tt.want)
+		}
+	}
+}
+
+func TestNewRequiresReceiptSecret(t *testing.T) {
+	if _, err := New(Options{RootURL: "https://push.example/%s"}, nopStore{}); err == nil {
+		t.Fatal("expected error for missing receipt_secret, got nil")
+	}
+}
@@ -0,0 +1,279 @@
+// Package telegram implements the `messenger.Messenger` interface for
+// delivering campaigns to Telegram subscribers via the Telegram Bot API.
+//
+// This package is the backend only: registering an instance under
+// cmd's messenger registry, exposing it as a campaign channel in the
+// settings UI, and the subscriber attrib ("telegram_chat_id") schema all
+// live outside this package and aren't present in this tree snapshot.
+// Wiring New's returned *Telegram into app.manager.AddMessenger (or
+// equivalent) at startup is expected of the integrator.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/knadh/listmonk/internal/messenger"
+	"golang.org/x/net/proxy"
+	"golang.org/x/time/rate"
+)
+
+// emName is the unique identifier used to register this messenger.
+const emName = "telegram"
+
+const (
+	apiBaseURL = "https://api.telegram.org/bot"
+
+	// Telegram's documented global and per-chat rate limits.
+	defGlobalRate = 30
+	defChatRate   = 1
+
+	// chatLimiterTTL is how long an idle per-chat limiter is kept before
+	// it's evicted. Campaigns can run to tens of thousands of distinct
+	// chat IDs, so t.chats must not grow unbounded for the life of the
+	// process.
+	chatLimiterTTL = time.Hour
+)
+
+// Options represents the Telegram bot configuration.
+type Options struct {
+	Token     string `json:"token"`
+	ParseMode string `json:"parse_mode"` // HTML or MarkdownV2.
+	ProxyURL  string `json:"proxy_url"`  // Optional SOCKS5 proxy, eg: socks5://host:port.
+
+	// Messages/sec. Telegram allows ~30/sec globally and 1/sec per chat.
+	MaxMsgSec  int `json:"max_msg_sec"`
+	MaxChatSec int `json:"max_chat_sec"`
+}
+
+// Telegram is the Telegram Bot API messenger.
+type Telegram struct {
+	o      Options
+	client *http.Client
+
+	global *rate.Limiter
+
+	mu        sync.Mutex
+	chats     map[string]*chatLimiter
+	nextSweep time.Time
+}
+
+// chatLimiter is a per-chat rate limiter along with the last time it was
+// used, so idle entries can be swept out of Telegram.chats.
+type chatLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// New returns a new instance of the Telegram messenger.
+func New(o Options) (*Telegram, error) {
+	if o.Token == "" {
+		return nil, fmt.Errorf("telegram: bot token is required")
+	}
+
+	if o.MaxMsgSec == 0 {
+		o.MaxMsgSec = defGlobalRate
+	}
+	if o.MaxChatSec == 0 {
+		o.MaxChatSec = defChatRate
+	}
+
+	tr := &http.Transport{}
+	if o.ProxyURL != "" {
+		u, err := url.Parse(o.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("telegram: invalid proxy_url: %v", err)
+		}
+		d, err := proxy.FromURL(u, proxy.Direct)
+		if err != nil {
+			return nil, fmt.Errorf("telegram: error setting up proxy: %v", err)
+		}
+		tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return d.Dial(network, addr)
+		}
+	}
+
+	return &Telegram{
+		o:      o,
+		client: &http.Client{Timeout: time.Second * 15, Transport: tr},
+		global: rate.NewLimiter(rate.Limit(o.MaxMsgSec), o.MaxMsgSec),
+		chats:  make(map[string]*chatLimiter),
+	}, nil
+}
+
+// Name returns the messenger's unique identifier.
+func (t *Telegram) Name() string {
+	return emName
+}
+
+// Push pushes a campaign message to the Telegram chat(s) in Message.To.
+// Message.To carries chat IDs or @channel handles read off a subscriber's
+// `telegram_chat_id` attrib.
+func (t *Telegram) Push(m messenger.Message) error {
+	for _, to := range m.To {
+		if len(m.Attachments) == 0 {
+			if err := t.limit(to); err != nil {
+				return err
+			}
+			if err := t.sendMessage(to, m); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, a := range m.Attachments {
+			if err := t.limit(to); err != nil {
+				return err
+			}
+			if err := t.sendAttachment(to, m, a); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Flush is a no-op: each Push call sends directly over HTTP, so there's no
+// locally buffered message queue to drain.
+func (t *Telegram) Flush() error {
+	return nil
+}
+
+// Close releases the pooled keep-alive connections held open by the
+// Telegram API client's transport.
+func (t *Telegram) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// limit blocks until it's safe to send to `to` without breaching either the
+// global or the per-chat Telegram rate limit.
+func (t *Telegram) limit(to string) error {
+	now := time.Now()
+
+	t.mu.Lock()
+	t.sweepLocked(now)
+	cl, ok := t.chats[to]
+	if !ok {
+		cl = &chatLimiter{limiter: rate.NewLimiter(rate.Limit(t.o.MaxChatSec), t.o.MaxChatSec)}
+		t.chats[to] = cl
+	}
+	cl.lastUsed = now
+	l := cl.limiter
+	t.mu.Unlock()
+
+	if err := t.global.Wait(context.Background()); err != nil {
+		return err
+	}
+	return l.Wait(context.Background())
+}
+
+// sweepLocked evicts per-chat limiters idle longer than chatLimiterTTL,
+// bounding t.chats' size across a long-running process sending to many
+// distinct chats. Must be called with t.mu held. It's throttled to run at
+// most once per chatLimiterTTL window rather than on every Push.
+func (t *Telegram) sweepLocked(now time.Time) {
+	if now.Before(t.nextSweep) {
+		return
+	}
+	t.nextSweep = now.Add(chatLimiterTTL)
+
+	for to, cl := range t.chats {
+		if now.Sub(cl.lastUsed) > chatLimiterTTL {
+			delete(t.chats, to)
+		}
+	}
+}
+
+// sendMessage sends a text-only message via the `sendMessage` API method.
+func (t *Telegram) sendMessage(to string, m messenger.Message) error {
+	parseMode := t.o.ParseMode
+	if h := m.Headers.Get("X-Telegram-Parse-Mode"); h != "" {
+		parseMode = h
+	}
+
+	body := map[string]interface{}{
+		"chat_id":    to,
+		"text":       string(m.Body),
+		"parse_mode": parseMode,
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return t.do("sendMessage", "application/json", bytes.NewReader(b))
+}
+
+// sendAttachment uploads a single attachment via `sendPhoto` or
+// `sendDocument` depending on the attachment's header.
+func (t *Telegram) sendAttachment(to string, m messenger.Message, a messenger.Attachment) error {
+	method := "sendDocument"
+	field := "document"
+	if a.Header.Get("Content-Type") == "image/jpeg" || a.Header.Get("Content-Type") == "image/png" {
+		method = "sendPhoto"
+		field = "photo"
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("chat_id", to); err != nil {
+		return err
+	}
+	if err := w.WriteField("caption", string(m.Body)); err != nil {
+		return err
+	}
+	fw, err := w.CreateFormFile(field, a.Name)
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(a.Content); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return t.do(method, w.FormDataContentType(), &buf)
+}
+
+// do executes a request against the Telegram Bot API and checks for the
+// `ok: false` envelope Telegram wraps errors in.
+func (t *Telegram) do(method, contentType string, body io.Reader) error {
+	u := apiBaseURL + t.o.Token + "/" + method
+
+	req, err := http.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("telegram: error decoding response: %v", err)
+	}
+	if !out.OK {
+		return fmt.Errorf("telegram: %s", out.Description)
+	}
+
+	return nil
+}